@@ -0,0 +1,172 @@
+package mdlrf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// archiveHTTPClient is shared by all "type: http" and "type: oci" module
+// fetches so that timeouts and transport settings are configured once.
+var archiveHTTPClient = &http.Client{Timeout: 5 * time.Minute}
+
+// importArchive downloads the module's URL, verifies it against Digest
+// (failing closed on any mismatch) and extracts it into AbsolutePath. It
+// returns the verified digest, which stands in for a commit SHA for
+// archive-backed modules in mdlr.yml and mdlr.lock.
+func (m *Module) importArchive() (string, error) {
+	wantSum := strings.TrimPrefix(m.Digest, "sha256:")
+	if wantSum == "" {
+		return "", ErrModuleMissingDigest
+	}
+
+	tmp, err := os.CreateTemp("", "mdlr-archive-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	resp, err := archiveHTTPClient.Get(m.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mdlrf: fetching %s: unexpected status %s", m.URL, resp.Status)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		return "", err
+	}
+	gotSum := hex.EncodeToString(h.Sum(nil))
+	if gotSum != wantSum {
+		return "", fmt.Errorf("%w: expected sha256:%s, got sha256:%s", ErrArchiveDigestMismatch, wantSum, gotSum)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	if err := os.RemoveAll(m.AbsolutePath); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(m.AbsolutePath, 0755); err != nil {
+		return "", err
+	}
+
+	if strings.HasSuffix(m.URL, ".zip") {
+		err = extractZip(tmp.Name(), m.AbsolutePath)
+	} else {
+		err = extractTarGz(tmp, m.AbsolutePath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "sha256:" + gotSum, nil
+}
+
+// extractTarGz extracts a gzip-compressed tarball into dest, rejecting any
+// entry whose path would escape dest (path traversal via "../"). An entry
+// with an absolute name is extracted beneath dest, not rejected.
+func extractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			f.Close()
+		}
+	}
+}
+
+// extractZip extracts a zip archive into dest, rejecting any entry whose
+// path would escape dest.
+func extractZip(path string, dest string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		target, err := safeJoin(dest, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		src, err := f.Open()
+		if err != nil {
+			return err
+		}
+		dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode())
+		if err != nil {
+			src.Close()
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		src.Close()
+		dst.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// safeJoin joins dest and name, rejecting the result if it would escape
+// dest (a zip-slip / path traversal attempt).
+func safeJoin(dest string, name string) (string, error) {
+	target := filepath.Join(dest, name)
+	if target != dest && !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrArchivePathTraversal, name)
+	}
+	return target, nil
+}
@@ -0,0 +1,119 @@
+package mdlrf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func serveArchive(t *testing.T, body []byte) (url string, digest string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	sum := sha256.Sum256(body)
+	return srv.URL + "/archive.tar.gz", "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestImportArchiveVerifiesDigestAndExtracts(t *testing.T) {
+	body := makeTarGz(t, map[string]string{"README.md": "hi there"})
+	url, digest := serveArchive(t, body)
+
+	m := &Module{Type: "http", URL: url, Digest: digest, AbsolutePath: filepath.Join(t.TempDir(), "mod")}
+	got, err := m.importArchive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != digest {
+		t.Fatalf("importArchive returned %q, want %q", got, digest)
+	}
+	data, err := os.ReadFile(filepath.Join(m.AbsolutePath, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hi there" {
+		t.Fatalf("extracted content = %q", data)
+	}
+}
+
+func TestImportArchiveRejectsDigestMismatch(t *testing.T) {
+	body := makeTarGz(t, map[string]string{"README.md": "hi there"})
+	url, _ := serveArchive(t, body)
+
+	m := &Module{Type: "http", URL: url, Digest: "sha256:" + hex.EncodeToString(make([]byte, 32)), AbsolutePath: filepath.Join(t.TempDir(), "mod")}
+	if _, err := m.importArchive(); err == nil {
+		t.Fatal("expected a digest mismatch error, got nil")
+	} else if !bytes.Contains([]byte(err.Error()), []byte("does not match")) {
+		t.Fatalf("expected a digest mismatch error, got: %v", err)
+	}
+}
+
+func TestExtractTarGzRejectsPathTraversal(t *testing.T) {
+	body := makeTarGz(t, map[string]string{"../escape.txt": "evil"})
+	dest := filepath.Join(t.TempDir(), "mod")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractTarGz(bytes.NewReader(body), dest); err == nil {
+		t.Fatal("expected extractTarGz to reject a path-traversal entry")
+	}
+}
+
+func TestExtractZipRejectsPathTraversal(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("../escape.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("evil")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "mod")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "a.zip")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractZip(archivePath, dest); err == nil {
+		t.Fatal("expected extractZip to reject a path-traversal entry")
+	}
+}
@@ -0,0 +1,63 @@
+package mdlrf
+
+import "time"
+
+// remoteLookupTimeout bounds how long a Backend's remote-only lookups
+// (LsRemote) may block, so an unreachable remote can't hang a command like
+// `mdlr list` indefinitely.
+const remoteLookupTimeout = 10 * time.Second
+
+// CloneOptions controls how a Backend materializes a module's working copy.
+type CloneOptions struct {
+	URL    string
+	Branch string
+	Depth  int
+}
+
+// Backend abstracts the VCS operations a Module needs in order to import
+// and update its working copy. ExecBackend shells out to the git binary;
+// GoGitBackend performs the same operations in-process via go-git. Both are
+// selected per MdlrFile via the `backend` field and resolved once by
+// MdlrCtx before being handed down to Module.
+type Backend interface {
+	// Clone materializes a fresh working copy of URL at dest, honoring
+	// Branch and Depth when set.
+	Clone(dest string, opts CloneOptions) error
+	// Fetch updates an existing working copy's remote-tracking refs.
+	// depth <= 0 means fetch full history.
+	Fetch(repoPath string, depth int) error
+	// Checkout moves the working copy at repoPath to ref, which may be a
+	// branch name or a commit SHA.
+	Checkout(repoPath string, ref string) error
+	// ResolveRef resolves ref (branch, tag or SHA) to a commit SHA without
+	// necessarily checking it out.
+	ResolveRef(repoPath string, ref string) (string, error)
+	// HeadCommit returns the commit SHA currently checked out at repoPath.
+	HeadCommit(repoPath string) (string, error)
+	// LsRemote returns the commit SHA that branch (or HEAD, when branch is
+	// empty) points to on the remote at url, without cloning or fetching.
+	// Implementations must bound the lookup to remoteLookupTimeout.
+	LsRemote(url string, branch string) (string, error)
+	// CommitsBetween reports how many commits separate from and to in the
+	// existing local working copy at repoPath, equivalent to `git rev-list
+	// --count from..to`.
+	CommitsBetween(repoPath string, from string, to string) (int, error)
+}
+
+const (
+	BackendExec  = "exec"
+	BackendGoGit = "go-git"
+)
+
+// resolveBackend returns the Backend named by the MdlrFile's `backend`
+// field, defaulting to ExecBackend when it is unset.
+func resolveBackend(name string) (Backend, error) {
+	switch name {
+	case "", BackendExec:
+		return &ExecBackend{}, nil
+	case BackendGoGit:
+		return &GoGitBackend{}, nil
+	default:
+		return nil, ErrUnknownBackend
+	}
+}
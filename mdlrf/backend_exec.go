@@ -0,0 +1,96 @@
+package mdlrf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ExecBackend implements Backend by shelling out to the git binary found on
+// PATH. It is the default backend and requires a working git installation.
+type ExecBackend struct{}
+
+func (b *ExecBackend) run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("mdlrf: git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (b *ExecBackend) Clone(dest string, opts CloneOptions) error {
+	args := []string{"clone"}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	args = append(args, opts.URL, dest)
+	_, err := b.run("", args...)
+	return err
+}
+
+func (b *ExecBackend) Fetch(repoPath string, depth int) error {
+	args := []string{"fetch", "--all"}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	_, err := b.run(repoPath, args...)
+	return err
+}
+
+func (b *ExecBackend) Checkout(repoPath string, ref string) error {
+	_, err := b.run(repoPath, "checkout", ref)
+	return err
+}
+
+func (b *ExecBackend) ResolveRef(repoPath string, ref string) (string, error) {
+	return b.run(repoPath, "rev-parse", ref)
+}
+
+func (b *ExecBackend) HeadCommit(repoPath string) (string, error) {
+	return b.run(repoPath, "rev-parse", "HEAD")
+}
+
+func (b *ExecBackend) LsRemote(url string, branch string) (string, error) {
+	ref := "HEAD"
+	if branch != "" {
+		ref = "refs/heads/" + branch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remoteLookupTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", url, ref)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("mdlrf: git ls-remote %s %s: %w: %s", url, ref, err, strings.TrimSpace(stderr.String()))
+	}
+	line := strings.TrimSpace(out.String())
+	if line == "" {
+		return "", fmt.Errorf("mdlrf: remote %s has no ref %s", url, ref)
+	}
+	return strings.Fields(line)[0], nil
+}
+
+func (b *ExecBackend) CommitsBetween(repoPath string, from string, to string) (int, error) {
+	out, err := b.run(repoPath, "rev-list", "--count", from+".."+to)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if _, err := fmt.Sscanf(out, "%d", &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
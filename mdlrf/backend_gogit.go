@@ -0,0 +1,184 @@
+package mdlrf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// GoGitBackend implements Backend entirely in-process using go-git, so mdlr
+// works in environments without a git binary available (minimal containers,
+// CI images). Authentication is read from the environment: SSH URLs use the
+// local SSH agent, HTTPS URLs use a token from MDLR_GIT_TOKEN (falling back
+// to GITHUB_TOKEN) as the password with a placeholder username.
+type GoGitBackend struct{}
+
+func gitAuth(url string) (transport.AuthMethod, error) {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		token := os.Getenv("MDLR_GIT_TOKEN")
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		if token == "" {
+			return nil, nil
+		}
+		return &http.BasicAuth{Username: "mdlr", Password: token}, nil
+	}
+	auth, err := ssh.NewSSHAgentAuth("git")
+	if err != nil {
+		// No SSH agent available; fall back to default key discovery done
+		// by go-git itself when auth is nil.
+		return nil, nil
+	}
+	return auth, nil
+}
+
+func (b *GoGitBackend) Clone(dest string, opts CloneOptions) error {
+	auth, err := gitAuth(opts.URL)
+	if err != nil {
+		return err
+	}
+	cloneOpts := &git.CloneOptions{
+		URL:  opts.URL,
+		Auth: auth,
+	}
+	if opts.Branch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
+	}
+	if opts.Depth > 0 {
+		cloneOpts.Depth = opts.Depth
+	}
+	_, err = git.PlainClone(dest, false, cloneOpts)
+	return err
+}
+
+func (b *GoGitBackend) Fetch(repoPath string, depth int) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return err
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return err
+	}
+	url := ""
+	if cfg := remote.Config(); cfg != nil && len(cfg.URLs) > 0 {
+		url = cfg.URLs[0]
+	}
+	auth, err := gitAuth(url)
+	if err != nil {
+		return err
+	}
+	fetchOpts := &git.FetchOptions{Auth: auth, Force: true}
+	if depth > 0 {
+		fetchOpts.Depth = depth
+	}
+	if err := remote.Fetch(fetchOpts); err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+	return nil
+}
+
+func (b *GoGitBackend) Checkout(repoPath string, ref string) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	hash, err := b.ResolveRef(repoPath, ref)
+	if err != nil {
+		return err
+	}
+	return wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(hash)})
+}
+
+func (b *GoGitBackend) ResolveRef(repoPath string, ref string) (string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+func (b *GoGitBackend) HeadCommit(repoPath string) (string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// LsRemote lists refs on the remote in-process via go-git's transport,
+// without cloning, bounded by remoteLookupTimeout.
+func (b *GoGitBackend) LsRemote(url string, branch string) (string, error) {
+	auth, err := gitAuth(url)
+	if err != nil {
+		return "", err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{url}})
+	ctx, cancel := context.WithTimeout(context.Background(), remoteLookupTimeout)
+	defer cancel()
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", err
+	}
+	refName := plumbing.HEAD
+	if branch != "" {
+		refName = plumbing.NewBranchReferenceName(branch)
+	}
+	for _, r := range refs {
+		if r.Name() == refName {
+			return r.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("mdlrf: remote %s has no ref %s", url, refName)
+}
+
+// CommitsBetween walks history backwards from to, counting commits until it
+// reaches from, mirroring `git rev-list --count from..to`.
+func (b *GoGitBackend) CommitsBetween(repoPath string, from string, to string) (int, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return 0, err
+	}
+	iter, err := repo.Log(&git.LogOptions{From: plumbing.NewHash(to)})
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+	fromHash := plumbing.NewHash(from)
+	count := 0
+	err = iter.ForEach(func(c *object.Commit) error {
+		if c.Hash == fromHash {
+			return storer.ErrStop
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
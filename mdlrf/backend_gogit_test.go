@@ -0,0 +1,159 @@
+package mdlrf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// initLocalRepo creates a non-bare git repository at dir with an initial
+// commit on its default branch and returns the *git.Repository plus that commit's SHA.
+func initLocalRepo(t *testing.T) (repo *git.Repository, dir string, firstCommit string) {
+	t.Helper()
+	dir = t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+	hash, err := wt.Commit("first", &git.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, dir, hash.String()
+}
+
+func TestGoGitBackendCloneAndHeadCommit(t *testing.T) {
+	_, upstream, firstCommit := initLocalRepo(t)
+
+	backend := &GoGitBackend{}
+	dest := filepath.Join(t.TempDir(), "clone")
+	if err := backend.Clone(dest, CloneOptions{URL: upstream}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := backend.HeadCommit(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != firstCommit {
+		t.Fatalf("HeadCommit() = %s, want %s", got, firstCommit)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); err != nil {
+		t.Fatalf("expected README.md to be checked out: %v", err)
+	}
+}
+
+func TestGoGitBackendFetchAndCheckout(t *testing.T) {
+	repo, upstream, firstCommit := initLocalRepo(t)
+
+	backend := &GoGitBackend{}
+	dest := filepath.Join(t.TempDir(), "clone")
+	if err := backend.Clone(dest, CloneOptions{URL: upstream}); err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(upstream, "README.md"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(1, 0)}
+	secondCommit, err := wt.Commit("second", &git.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.Fetch(dest, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Checkout(dest, secondCommit.String()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := backend.HeadCommit(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != secondCommit.String() {
+		t.Fatalf("HeadCommit() after checkout = %s, want %s", got, secondCommit.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "v2" {
+		t.Fatalf("README.md content = %q after checkout to second commit", data)
+	}
+
+	if err := backend.Checkout(dest, firstCommit); err != nil {
+		t.Fatal(err)
+	}
+	got, err = backend.HeadCommit(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != firstCommit {
+		t.Fatalf("HeadCommit() after checking out firstCommit = %s, want %s", got, firstCommit)
+	}
+}
+
+func TestGoGitBackendResolveRef(t *testing.T) {
+	_, upstream, firstCommit := initLocalRepo(t)
+
+	backend := &GoGitBackend{}
+	dest := filepath.Join(t.TempDir(), "clone")
+	if err := backend.Clone(dest, CloneOptions{URL: upstream}); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := backend.ResolveRef(dest, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != firstCommit {
+		t.Fatalf("ResolveRef(HEAD) = %s, want %s", resolved, firstCommit)
+	}
+}
+
+func TestGitAuthFallsBackToNilForHTTPSWithoutToken(t *testing.T) {
+	for _, env := range []string{"MDLR_GIT_TOKEN", "GITHUB_TOKEN"} {
+		t.Setenv(env, "")
+	}
+	auth, err := gitAuth("https://example.test/repo.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth != nil {
+		t.Fatalf("expected nil auth without a token configured, got %v", auth)
+	}
+}
+
+func TestGitAuthUsesTokenForHTTPS(t *testing.T) {
+	t.Setenv("MDLR_GIT_TOKEN", "s3cr3t")
+	auth, err := gitAuth("https://example.test/repo.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth == nil {
+		t.Fatal("expected a non-nil auth when MDLR_GIT_TOKEN is set")
+	}
+}
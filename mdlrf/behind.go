@@ -0,0 +1,83 @@
+package mdlrf
+
+// BehindReport describes how far a module's pinned commit trails the tip of
+// its tracked branch on the remote.
+type BehindReport struct {
+	Name       string
+	Branch     string
+	Commit     string
+	RemoteSHA  string
+	AheadCount int
+}
+
+// Behind consults the remote for each module (or just name, when non-empty)
+// and reports how many commits the tracked branch is ahead of the pinned
+// commit, without cloning or modifying the working copy. It goes through
+// ctx.Backend(), so it works with either ExecBackend or GoGitBackend, and
+// works even for modules that have never been imported locally. Archive
+// modules (type: http/oci) are skipped: they are pinned by content digest,
+// not tracked against a git remote.
+func (ctx *MdlrCtx) Behind(name string) ([]BehindReport, error) {
+	if err := ctx.loadFile(); err != nil {
+		return nil, err
+	}
+	return ctx.behindLoaded(name)
+}
+
+// behindLoaded is the body of Behind, factored out so callers that already
+// hold a loaded MdlrCtx (e.g. List) can reuse it without re-entering
+// loadFile, which would fail with ErrMdlrFileAlreadyLoaded.
+func (ctx *MdlrCtx) behindLoaded(name string) ([]BehindReport, error) {
+	if len(ctx.MdlrFile.Modules) == 0 {
+		return nil, ErrNoModules
+	}
+	backend, err := ctx.Backend()
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]BehindReport, 0, len(ctx.MdlrFile.Modules))
+	for _, n := range specificNames(ctx.MdlrFile, name) {
+		m, exist := ctx.MdlrFile.Modules[n]
+		if !exist {
+			return nil, ErrModuleNameNotExist
+		}
+		if m.IsArchive() {
+			continue
+		}
+		remoteSHA, err := backend.LsRemote(m.URL, m.Branch)
+		if err != nil {
+			return nil, err
+		}
+		ahead := 0
+		if remoteSHA != m.Commit {
+			ahead, err = countCommitsBetween(backend, m, remoteSHA)
+			if err != nil {
+				return nil, err
+			}
+		}
+		reports = append(reports, BehindReport{
+			Name:       n,
+			Branch:     m.Branch,
+			Commit:     m.Commit,
+			RemoteSHA:  remoteSHA,
+			AheadCount: ahead,
+		})
+	}
+	return reports, nil
+}
+
+// countCommitsBetween reports how many commits separate m's pinned commit
+// from remoteSHA when a local clone is available. It is best-effort: when
+// the module hasn't been imported yet, or the local history walk fails,
+// there is nothing reliable to report, so it returns 1 to indicate "behind,
+// exact count unknown".
+func countCommitsBetween(backend Backend, m *Module, remoteSHA string) (int, error) {
+	if _, err := pathExists(m.AbsolutePath); err != nil {
+		return 1, nil
+	}
+	count, err := backend.CommitsBetween(m.AbsolutePath, m.Commit, remoteSHA)
+	if err != nil {
+		return 1, nil
+	}
+	return count, nil
+}
@@ -3,12 +3,32 @@ package mdlrf
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 type MdlrCtx struct {
 	IsFileReady bool
 	FilePath    string
 	MdlrFile    *MdlrFile
+
+	LockPath string
+	LockFile *MdlrLockFile
+
+	backend Backend
+}
+
+// Backend resolves (and caches) the Backend selected by the loaded
+// MdlrFile's `backend` field.
+func (ctx *MdlrCtx) Backend() (Backend, error) {
+	if ctx.backend != nil {
+		return ctx.backend, nil
+	}
+	backend, err := resolveBackend(ctx.MdlrFile.Backend)
+	if err != nil {
+		return nil, err
+	}
+	ctx.backend = backend
+	return backend, nil
 }
 
 func NewMdlrCtxForCmd() (*MdlrCtx, error) {
@@ -35,6 +55,21 @@ func (ctx *MdlrCtx) loadFile() error {
 	return nil
 }
 
+func (ctx *MdlrCtx) loadLockFile() error {
+	if ctx.LockFile != nil {
+		return nil
+	}
+	if ctx.LockPath == "" {
+		ctx.LockPath = filepath.Join(ctx.MdlrFile.ParentDirectory, LockFileName)
+	}
+	lf := &MdlrLockFile{}
+	if err := lf.Load(ctx.LockPath); err != nil {
+		return err
+	}
+	ctx.LockFile = lf
+	return nil
+}
+
 func (ctx *MdlrCtx) Init() error {
 	if err := ctx.loadFile(); err == nil {
 		return ErrMdlrFileAlreadyExists
@@ -57,7 +92,16 @@ func (ctx *MdlrCtx) List() (string, error) {
 	}
 	items := make([]string, 0, len(ctx.MdlrFile.Modules))
 	for _, m := range ctx.MdlrFile.Modules {
-		items = append(items, fmt.Sprintf("%s:%s (%s) %s@%s(%s) [current=%s]", m.Name, m.Path, m.Type, m.URL, m.Branch, m.Commit, m.Status(true)))
+		status := fmt.Sprintf("[current=%s]", m.Status(true))
+		if reports, err := ctx.behindLoaded(m.Name); err != nil {
+			status = fmt.Sprintf("[current=%s, behind-check failed: %s]", m.Status(true), err)
+		} else if len(reports) == 1 {
+			r := reports[0]
+			if r.AheadCount > 0 {
+				status = fmt.Sprintf("[current=%s, %d behind origin/%s]", m.Status(true), r.AheadCount, m.Branch)
+			}
+		}
+		items = append(items, fmt.Sprintf("%s:%s (%s) %s@%s(%s) %s", m.Name, m.Path, m.Type, m.URL, m.Branch, m.Commit, status))
 	}
 	out := fmt.Sprintf("Modules: %d", len(items))
 	for n, val := range items {
@@ -66,7 +110,7 @@ func (ctx *MdlrCtx) List() (string, error) {
 	return out, nil
 }
 
-func (ctx *MdlrCtx) Add(name string, mType string, path string, url string, branch string, commit string) error {
+func (ctx *MdlrCtx) Add(name string, mType string, path string, url string, branch string, commit string, digest string) error {
 	err := ctx.loadFile()
 	if err != nil {
 		return err
@@ -74,12 +118,21 @@ func (ctx *MdlrCtx) Add(name string, mType string, path string, url string, bran
 	if _, exist := ctx.MdlrFile.Modules[name]; exist {
 		return ErrModuleNameAlreadyInUse
 	}
+	if mType == "" {
+		root, err := ResolveRepoRoot(url)
+		if err != nil {
+			return err
+		}
+		mType = root.VCS
+		url = root.URL
+	}
 	ctx.MdlrFile.Modules[name] = &Module{
 		Type:   mType,
 		Path:   path,
 		URL:    url,
 		Branch: branch,
 		Commit: commit,
+		Digest: digest,
 	}
 	ctx.MdlrFile.Modules[name].Prepare(name, ctx.MdlrFile.ParentDirectory)
 	err = ctx.MdlrFile.Modules[name].Validate()
@@ -111,7 +164,13 @@ func (ctx *MdlrCtx) Remove(name string, dropFiles bool) error {
 	return ctx.MdlrFile.Persist()
 }
 
-func (ctx *MdlrCtx) Import(specificName string, force bool) error {
+// Import fetches and checks out every module (or just specificName). When
+// frozen is true it first verifies that mdlr.lock agrees with mdlr.yml for
+// every module in scope and refuses to touch the filesystem otherwise;
+// when frozen is false (the default) it verifies, after checkout, that the
+// recomputed tree hash matches the lock entry for the resolved commit,
+// rejecting a working copy that was tampered with since it was last locked.
+func (ctx *MdlrCtx) Import(specificName string, force bool, frozen bool) error {
 	err := ctx.loadFile()
 	if err != nil {
 		return err
@@ -119,38 +178,124 @@ func (ctx *MdlrCtx) Import(specificName string, force bool) error {
 	if len(ctx.MdlrFile.Modules) == 0 {
 		return ErrNoModules
 	}
+	if err := ctx.loadLockFile(); err != nil {
+		return err
+	}
+	backend, err := ctx.Backend()
+	if err != nil {
+		return err
+	}
+	names := specificNames(ctx.MdlrFile, specificName)
+	if frozen {
+		for _, name := range names {
+			if _, exist := ctx.MdlrFile.Modules[name]; !exist {
+				return ErrModuleNameNotExist
+			}
+			m := ctx.MdlrFile.Modules[name]
+			locked, exist := ctx.LockFile.Modules[name]
+			if !exist || locked.Commit != m.Pin() {
+				return ErrLockDrift
+			}
+		}
+	}
 	var runForRepo = func(name string) error {
 		if _, exist := ctx.MdlrFile.Modules[name]; !exist {
 			return ErrModuleNameNotExist
 		}
+		m := ctx.MdlrFile.Modules[name]
 		if force {
-			dirPath := ctx.MdlrFile.Modules[name].AbsolutePath
-			os.RemoveAll(dirPath)
+			os.RemoveAll(m.AbsolutePath)
 		}
-		return ctx.MdlrFile.Modules[name].Import(ctx.MdlrFile.Modules[name].Branch, ctx.MdlrFile.Modules[name].Commit, ctx.MdlrFile.Modules[name].Depth)
-	}
-	if specificName != "" {
-		err := runForRepo(specificName)
+		vendored := false
+		if locked, exist := ctx.LockFile.Modules[name]; exist {
+			vendored = locked.Vendored
+		}
+		c, err := m.Import(backend, m.Branch, m.Commit, m.Depth, vendored)
 		if err != nil {
 			return err
 		}
-	} else {
-		for _, m := range ctx.MdlrFile.Modules {
-			if err := runForRepo(m.Name); err != nil {
-				return err
-			}
+		if m.IsArchive() {
+			m.Digest = c
+		} else {
+			m.Commit = c
+		}
+		hash, err := hashTree(m.AbsolutePath)
+		if err != nil {
+			return err
+		}
+		if locked, exist := ctx.LockFile.Modules[name]; exist && locked.Commit == c && locked.TreeHash != hash {
+			return ErrTreeTampered
+		}
+		ctx.LockFile.Modules[name] = &LockedModule{Commit: c, TreeHash: hash}
+		if m.Vendor {
+			return ctx.applyVendor(name)
+		}
+		return nil
+	}
+	for _, name := range names {
+		if err := runForRepo(name); err != nil {
+			return err
 		}
 	}
+	if err := ctx.LockFile.Persist(); err != nil {
+		return err
+	}
 	return ctx.MdlrFile.Persist()
 }
 
+// specificNames returns []string{name} when name is non-empty, otherwise
+// every module name defined in f.
+func specificNames(f *MdlrFile, name string) []string {
+	if name != "" {
+		return []string{name}
+	}
+	names := make([]string, 0, len(f.Modules))
+	for n := range f.Modules {
+		names = append(names, n)
+	}
+	return names
+}
+
+// Verify recomputes the tree hash for name (or every module when name is
+// empty) and reports any drift against mdlr.lock.
+func (ctx *MdlrCtx) Verify(name string) ([]VerifyReport, error) {
+	if err := ctx.loadFile(); err != nil {
+		return nil, err
+	}
+	if err := ctx.loadLockFile(); err != nil {
+		return nil, err
+	}
+	reports := make([]VerifyReport, 0, len(ctx.MdlrFile.Modules))
+	for _, n := range specificNames(ctx.MdlrFile, name) {
+		m, exist := ctx.MdlrFile.Modules[n]
+		if !exist {
+			return nil, ErrModuleNameNotExist
+		}
+		locked, exist := ctx.LockFile.Modules[n]
+		if !exist {
+			reports = append(reports, VerifyReport{Name: n, OK: false, Reason: "not present in mdlr.lock"})
+			continue
+		}
+		hash, err := hashTree(m.AbsolutePath)
+		if err != nil {
+			return nil, err
+		}
+		report := VerifyReport{Name: n, Expected: locked.TreeHash, Actual: hash, OK: hash == locked.TreeHash}
+		if !report.OK {
+			report.Reason = "tree hash does not match mdlr.lock"
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
 func (ctx *MdlrCtx) Update(specificName, branch, commit string, force bool) error {
 	if commit == "" {
 		commit = "HEAD"
 	}
 	var err error
 	if force {
-		err = ctx.Import(specificName, force)
+		err = ctx.Import(specificName, force, false)
 		if err != nil {
 			return err
 		}
@@ -163,6 +308,13 @@ func (ctx *MdlrCtx) Update(specificName, branch, commit string, force bool) erro
 			return ErrNoModules
 		}
 	}
+	if err := ctx.loadLockFile(); err != nil {
+		return err
+	}
+	backend, err := ctx.Backend()
+	if err != nil {
+		return err
+	}
 	var runForRepo = func(name string) error {
 		if _, exist := ctx.MdlrFile.Modules[name]; !exist {
 			return ErrModuleNameNotExist
@@ -171,7 +323,24 @@ func (ctx *MdlrCtx) Update(specificName, branch, commit string, force bool) erro
 		if b == "" {
 			b = ctx.MdlrFile.Modules[name].Branch
 		}
-		c, err := ctx.MdlrFile.Modules[name].Update(b, commit)
+		vendored := false
+		if locked, exist := ctx.LockFile.Modules[name]; exist {
+			vendored = locked.Vendored
+		}
+		c, err := ctx.MdlrFile.Modules[name].Update(backend, b, commit, vendored)
+		if err == nil {
+			// Record the updated state in mdlr.lock, keeping the vendoring
+			// details of a module that Vendor already flattened.
+			var hash string
+			if hash, err = hashTree(ctx.MdlrFile.Modules[name].AbsolutePath); err == nil {
+				entry := &LockedModule{Commit: c, TreeHash: hash}
+				if vendored {
+					entry.Vendored, entry.Paths = true, ctx.LockFile.Modules[name].Paths
+				}
+				ctx.LockFile.Modules[name] = entry
+				err = ctx.LockFile.Persist()
+			}
+		}
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,23 @@
+package mdlrf
+
+import "errors"
+
+var (
+	ErrMdlrFileNotExist       = errors.New("mdlrf: mdlr.yml does not exist")
+	ErrMdlrFileAlreadyExists  = errors.New("mdlrf: mdlr.yml already exists")
+	ErrMdlrFileAlreadyLoaded  = errors.New("mdlrf: mdlr.yml is already loaded")
+	ErrMdlrFileInvalidPath    = errors.New("mdlrf: no path to mdlr.yml was given")
+	ErrNoModules              = errors.New("mdlrf: there aren't any modules defined")
+	ErrModuleNameAlreadyInUse = errors.New("mdlrf: a module with that name already exists")
+	ErrModuleNameNotExist     = errors.New("mdlrf: no module with that name exists")
+	ErrModuleMissingURL       = errors.New("mdlrf: module is missing a url")
+	ErrModuleInvalidType      = errors.New("mdlrf: module has an unsupported type")
+	ErrModuleMissingDigest    = errors.New("mdlrf: http/oci modules require a sha256: digest")
+	ErrUnknownBackend         = errors.New("mdlrf: unknown backend")
+	ErrLockDrift              = errors.New("mdlrf: mdlr.lock disagrees with mdlr.yml; run import without --frozen to update it")
+	ErrTreeTampered           = errors.New("mdlrf: checked out tree does not match the hash recorded in mdlr.lock")
+	ErrArchiveDigestMismatch  = errors.New("mdlrf: downloaded archive does not match the expected digest")
+	ErrArchivePathTraversal   = errors.New("mdlrf: archive entry would extract outside the module directory")
+	ErrRepoRootNotFound       = errors.New("mdlrf: could not determine the VCS and URL for that import path")
+	ErrVCSNotSupported        = errors.New("mdlrf: only git is supported for auto-detected modules")
+)
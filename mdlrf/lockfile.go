@@ -0,0 +1,123 @@
+package mdlrf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LockFileName is the name of the lockfile mdlr writes next to mdlr.yml.
+const LockFileName = "mdlr.lock"
+
+// VerifyReport describes the result of comparing a module's checked-out
+// tree against its mdlr.lock entry.
+type VerifyReport struct {
+	Name     string
+	OK       bool
+	Expected string
+	Actual   string
+	Reason   string
+}
+
+// LockedModule records the exact state a module was in the last time it was
+// successfully imported or updated, so a later Import can detect drift
+// between mdlr.yml, the lockfile and the actual working copy.
+type LockedModule struct {
+	Commit   string `yaml:"commit"`
+	TreeHash string `yaml:"tree_hash"`
+
+	// Vendored and Paths are set once Vendor has flattened this module; the
+	// TreeHash is then computed after filtering rather than over a full
+	// checkout. Import and Update read Vendored to skip fetching a module
+	// whose .git directory has been removed.
+	Vendored bool     `yaml:"vendored,omitempty"`
+	Paths    []string `yaml:"paths,omitempty"`
+}
+
+// MdlrLockFile is the in-memory representation of mdlr.lock.
+type MdlrLockFile struct {
+	Modules map[string]*LockedModule `yaml:"modules"`
+
+	path string
+}
+
+// NewMdlrLockFile returns an empty lockfile that Persist will write to path.
+func NewMdlrLockFile(path string) *MdlrLockFile {
+	return &MdlrLockFile{Modules: map[string]*LockedModule{}, path: path}
+}
+
+// Load reads mdlr.lock at path. A missing lockfile is not an error: it
+// simply yields an empty lockfile, since it is created lazily on first
+// Import.
+func (l *MdlrLockFile) Load(path string) error {
+	l.path = path
+	l.Modules = map[string]*LockedModule{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return yaml.Unmarshal(data, l)
+}
+
+// Persist writes the lockfile back to its path.
+func (l *MdlrLockFile) Persist() error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(l.path, data, 0644)
+}
+
+// hashTree computes a SHA-256 hash over every regular file under root,
+// excluding the top-level ".git" entry, so that the same commit always produces
+// the same hash regardless of how it was checked out.
+func hashTree(root string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) > 0 && parts[0] == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if _, err := io.WriteString(h, rel+"\n"); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
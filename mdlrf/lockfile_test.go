@@ -0,0 +1,51 @@
+package mdlrf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashTreeIgnoresGitDirAndIsStable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := hashTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := hashTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != after {
+		t.Fatalf("hashTree changed after adding .git: before=%s after=%s", before, after)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tampered, err := hashTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tampered == after {
+		t.Fatal("hashTree did not change after a tracked file was modified")
+	}
+}
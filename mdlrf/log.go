@@ -0,0 +1,10 @@
+package mdlrf
+
+import (
+	"log"
+	"os"
+)
+
+// Log is the package-wide logger used to surface progress from long-running
+// module operations (clone, fetch, checkout) to the user.
+var Log = log.New(os.Stdout, "", 0)
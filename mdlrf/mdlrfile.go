@@ -0,0 +1,65 @@
+package mdlrf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MdlrFile is the in-memory representation of mdlr.yml.
+type MdlrFile struct {
+	// Backend selects the VCS implementation used for every module in this
+	// file: BackendExec (the default, shells out to git) or BackendGoGit
+	// (in-process, via go-git).
+	Backend string `yaml:"backend,omitempty"`
+
+	Modules map[string]*Module `yaml:"modules"`
+
+	ParentDirectory string `yaml:"-"`
+	path            string
+}
+
+// NewMdlrFile returns an empty MdlrFile ready to be Prepare'd and Persisted.
+func NewMdlrFile() *MdlrFile {
+	return &MdlrFile{Modules: map[string]*Module{}}
+}
+
+// Prepare fills in the fields derived from the manifest's location.
+func (f *MdlrFile) Prepare(path string) {
+	f.path = path
+	f.ParentDirectory = filepath.Dir(path)
+}
+
+// Load reads and parses the mdlr.yml at path, preparing every module it
+// contains. It returns ErrMdlrFileNotExist when the file is absent.
+func (f *MdlrFile) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrMdlrFileNotExist
+		}
+		return err
+	}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return err
+	}
+	if f.Modules == nil {
+		f.Modules = map[string]*Module{}
+	}
+	f.Prepare(path)
+	for name, m := range f.Modules {
+		m.Prepare(name, f.ParentDirectory)
+	}
+	return nil
+}
+
+// Persist writes the manifest back to its path.
+func (f *MdlrFile) Persist() error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.path, data, 0644)
+}
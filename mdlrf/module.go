@@ -0,0 +1,172 @@
+package mdlrf
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Module describes a single dependency tracked in mdlr.yml: where it comes
+// from, where it is checked out, and which ref it is pinned to.
+type Module struct {
+	Name   string `yaml:"-"`
+	Type   string `yaml:"type"`
+	Path   string `yaml:"path,omitempty"`
+	URL    string `yaml:"url"`
+	Branch string `yaml:"branch,omitempty"`
+	Commit string `yaml:"commit,omitempty"`
+	Depth  int    `yaml:"depth,omitempty"`
+
+	// Digest pins a "type: http" or "type: oci" module to a content hash
+	// (e.g. "sha256:<hex>"), in place of Commit, since an archive has no
+	// commit of its own.
+	Digest string `yaml:"digest,omitempty"`
+
+	// Vendor, when true, makes `mdlr import` flatten this module after a
+	// successful fetch: its VCS metadata is stripped and, when Paths is
+	// set, only the matching files are kept.
+	Vendor bool `yaml:"vendor,omitempty"`
+	// Paths is a set of glob filters (e.g. "src/**", "LICENSE") applied
+	// when vendoring; files that match none of them are removed. Empty
+	// means keep everything.
+	Paths []string `yaml:"paths,omitempty"`
+
+	AbsolutePath string `yaml:"-"`
+}
+
+// IsArchive reports whether the module is fetched as a downloaded archive
+// (type: http or type: oci) rather than cloned from a VCS.
+func (m *Module) IsArchive() bool {
+	return m.Type == "http" || m.Type == "oci"
+}
+
+// Prepare fills in the fields derived from the module's name and the
+// mdlr.yml's parent directory. It must run before Validate, Import or
+// Update are called.
+func (m *Module) Prepare(name string, parentDirectory string) {
+	m.Name = name
+	if m.Path == "" {
+		m.Path = name
+	}
+	m.AbsolutePath = filepath.Join(parentDirectory, m.Path)
+}
+
+// Validate checks that the module has enough information to be imported.
+func (m *Module) Validate() error {
+	if m.Type == "" {
+		m.Type = "git"
+	}
+	switch m.Type {
+	case "git":
+		if m.URL == "" {
+			return ErrModuleMissingURL
+		}
+		return nil
+	case "http", "oci":
+		if m.URL == "" {
+			return ErrModuleMissingURL
+		}
+		if !strings.HasPrefix(m.Digest, "sha256:") {
+			return ErrModuleMissingDigest
+		}
+		return nil
+	default:
+		return ErrModuleInvalidType
+	}
+}
+
+// Import materializes the module's working copy using backend: cloning it
+// if it doesn't exist yet, otherwise fetching, then checking out commit
+// (falling back to branch when commit is empty or "HEAD"). It returns the
+// commit that ended up checked out. vendored must be true only when
+// mdlr.lock already recorded this module as vendored (set by Vendor), since
+// that's the one state where skipping fetch/checkout is actually correct;
+// a directory that merely happens to exist is not enough on its own.
+func (m *Module) Import(backend Backend, branch string, commit string, depth int, vendored bool) (string, error) {
+	if m.IsArchive() {
+		return m.importArchive()
+	}
+	if m.Vendor && vendored && m.alreadyVendoredOnDisk() {
+		// Vendor already flattened this module, which deletes .git, so
+		// there is nothing left to fetch or check out against; the working
+		// copy itself is the pinned state.
+		return m.Pin(), nil
+	}
+	ref := branch
+	if commit != "" && commit != "HEAD" {
+		ref = commit
+	}
+	if _, err := pathExists(m.AbsolutePath); err != nil {
+		if err := backend.Clone(m.AbsolutePath, CloneOptions{URL: m.URL, Branch: branch, Depth: depth}); err != nil {
+			return "", err
+		}
+	} else if err := backend.Fetch(m.AbsolutePath, depth); err != nil {
+		return "", err
+	}
+	if ref != "" {
+		if err := backend.Checkout(m.AbsolutePath, ref); err != nil {
+			return "", err
+		}
+	}
+	return backend.HeadCommit(m.AbsolutePath)
+}
+
+// Update fetches the latest refs for the module and checks out branch (or
+// commit, when set to something other than "HEAD"), returning the resulting
+// commit SHA. vendored has the same meaning as in Import.
+func (m *Module) Update(backend Backend, branch string, commit string, vendored bool) (string, error) {
+	if m.IsArchive() {
+		return m.importArchive()
+	}
+	if m.Vendor && vendored && m.alreadyVendoredOnDisk() {
+		// Same as Import: a vendored working copy has no .git left to
+		// fetch against, so there is nothing to update in place.
+		return m.Pin(), nil
+	}
+	if err := backend.Fetch(m.AbsolutePath, m.Depth); err != nil {
+		return "", err
+	}
+	ref := branch
+	if commit != "" && commit != "HEAD" {
+		ref = commit
+	}
+	if ref != "" {
+		if err := backend.Checkout(m.AbsolutePath, ref); err != nil {
+			return "", err
+		}
+	}
+	return backend.HeadCommit(m.AbsolutePath)
+}
+
+// alreadyVendoredOnDisk confirms AbsolutePath both exists and, consistent
+// with what applyVendor actually did to it, has no .git directory left.
+// This is a sanity check on top of the caller-supplied "vendored" flag, not
+// a substitute for it: a freshly created or leftover directory can satisfy
+// this just as well as a genuinely vendored one.
+func (m *Module) alreadyVendoredOnDisk() bool {
+	if _, err := pathExists(m.AbsolutePath); err != nil {
+		return false
+	}
+	_, err := pathExists(filepath.Join(m.AbsolutePath, ".git"))
+	return err != nil
+}
+
+// Pin returns the identifier mdlr.lock keys this module's state by: the
+// commit SHA for VCS-backed modules, or the content digest for
+// archive-backed ones.
+func (m *Module) Pin() string {
+	if m.IsArchive() {
+		return m.Digest
+	}
+	return m.Commit
+}
+
+// Status reports the module's current pin (see Pin). When short is true a
+// commit SHA is truncated to the usual 7-character abbreviation used in
+// `mdlr list` output; an archive module's digest is always returned in full.
+func (m *Module) Status(short bool) string {
+	pin := m.Pin()
+	if short && len(pin) > 7 && !m.IsArchive() {
+		return pin[:7]
+	}
+	return pin
+}
@@ -0,0 +1,83 @@
+package mdlrf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recordingBackend counts Clone/Fetch calls so tests can assert whether
+// Module.Import actually touched the backend or took the vendored
+// short-circuit.
+type recordingBackend struct {
+	cloneCalls int
+	fetchCalls int
+}
+
+func (b *recordingBackend) Clone(dest string, opts CloneOptions) error {
+	b.cloneCalls++
+	return os.MkdirAll(filepath.Join(dest, ".git"), 0755)
+}
+func (b *recordingBackend) Fetch(repoPath string, depth int) error {
+	b.fetchCalls++
+	return nil
+}
+func (b *recordingBackend) Checkout(repoPath string, ref string) error { return nil }
+func (b *recordingBackend) ResolveRef(repoPath string, ref string) (string, error) {
+	return ref, nil
+}
+func (b *recordingBackend) HeadCommit(repoPath string) (string, error) { return "headsha", nil }
+func (b *recordingBackend) LsRemote(url string, branch string) (string, error) {
+	return "remotesha", nil
+}
+func (b *recordingBackend) CommitsBetween(repoPath string, from string, to string) (int, error) {
+	return 0, nil
+}
+
+func TestImportSkipsFetchOnlyWhenActuallyVendored(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mod")
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("vendored content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &Module{Vendor: true, Commit: "pinned-sha", AbsolutePath: path}
+	backend := &recordingBackend{}
+
+	// A pre-existing directory that was never actually vendored (e.g. a
+	// leftover from a failed import) must not be treated as vendored just
+	// because it exists: the caller-supplied vendored flag is false, so
+	// Import should still fetch/clone.
+	if _, err := m.Import(backend, "main", "", 0, false); err != nil {
+		t.Fatal(err)
+	}
+	if backend.fetchCalls != 1 {
+		t.Fatalf("expected Import to fetch a directory that was never vendored, fetchCalls=%d", backend.fetchCalls)
+	}
+}
+
+func TestImportSkipsFetchForGenuinelyVendoredModule(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mod")
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("vendored content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &Module{Vendor: true, Commit: "pinned-sha", AbsolutePath: path}
+	backend := &recordingBackend{}
+
+	got, err := m.Import(backend, "main", "", 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != m.Pin() {
+		t.Fatalf("Import returned %q, want pinned commit %q", got, m.Pin())
+	}
+	if backend.cloneCalls != 0 || backend.fetchCalls != 0 {
+		t.Fatalf("expected no backend calls for a genuinely vendored module, clone=%d fetch=%d", backend.cloneCalls, backend.fetchCalls)
+	}
+}
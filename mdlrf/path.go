@@ -0,0 +1,43 @@
+package mdlrf
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// MdlrFileName is the name of the manifest file mdlr looks for in the
+// current directory and its ancestors.
+const MdlrFileName = "mdlr.yml"
+
+// getMdlrFilePathForCmd locates the mdlr.yml that applies to the current
+// working directory, walking up towards the filesystem root the same way
+// git locates a .git directory. If none is found, it falls back to a
+// mdlr.yml in the current directory so that Init has somewhere to write.
+func getMdlrFilePathForCmd() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(dir, MdlrFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cwd, MdlrFileName), nil
+}
+
+// pathExists stats path, returning an error when it does not exist so it
+// can be used directly in an `if _, err := pathExists(p); err != nil` check.
+func pathExists(path string) (os.FileInfo, error) {
+	return os.Stat(path)
+}
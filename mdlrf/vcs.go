@@ -0,0 +1,89 @@
+package mdlrf
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RepoRoot is the result of resolving an import-path-like string down to a
+// VCS type and canonical clone URL, the same shape as golang.org/x/tools's
+// vcs.RepoRoot but scoped to what mdlr needs. The go-import meta tag this
+// is parsed from can name "git", "hg" or "svn", but mdlr only has a git
+// Backend, so ResolveRepoRoot rejects anything other than "git" with
+// ErrVCSNotSupported instead of returning a RepoRoot it can't act on.
+type RepoRoot struct {
+	VCS string // always "git"
+	URL string // canonical clone URL
+}
+
+// wellKnownHosts maps a hostname prefix straight to "git" without needing a
+// network round-trip, covering the overwhelming majority of modules added
+// via `mdlr add`.
+var wellKnownHosts = map[string]string{
+	"github.com":    "git",
+	"gitlab.com":    "git",
+	"bitbucket.org": "git",
+}
+
+var goGetMetaTag = regexp.MustCompile(`<meta\s+name=["']go-import["']\s+content=["']([^ ]+)\s+(\w+)\s+([^"']+)["']`)
+
+// goImportHTTPClient is used for the "?go-get=1" discovery request, mirroring
+// archiveHTTPClient's pattern of a shared, overridable client per fetch kind.
+var goImportHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// ResolveRepoRoot determines the VCS and canonical clone URL for path. path
+// may already be a full URL (https://github.com/user/repo) or a bare
+// import-path-like string (github.com/user/repo), mirroring the shorthand
+// `go get` accepts.
+func ResolveRepoRoot(path string) (*RepoRoot, error) {
+	normalized := strings.TrimSuffix(path, "/")
+	normalized = strings.TrimPrefix(normalized, "https://")
+	normalized = strings.TrimPrefix(normalized, "http://")
+
+	parts := strings.SplitN(normalized, "/", 2)
+	if len(parts) == 0 || parts[0] == "" {
+		return nil, ErrRepoRootNotFound
+	}
+	host := parts[0]
+
+	if vcs, ok := wellKnownHosts[host]; ok {
+		segments := strings.Split(normalized, "/")
+		if len(segments) < 3 {
+			return nil, ErrRepoRootNotFound
+		}
+		url := fmt.Sprintf("https://%s/%s/%s", segments[0], segments[1], strings.TrimSuffix(segments[2], ".git"))
+		return &RepoRoot{VCS: vcs, URL: url + ".git"}, nil
+	}
+
+	return discoverGoImportMeta(normalized)
+}
+
+// discoverGoImportMeta fetches https://<path>?go-get=1 and parses the
+// go-import meta tag the same way `go get` does for custom domains.
+func discoverGoImportMeta(path string) (*RepoRoot, error) {
+	resp, err := goImportHTTPClient.Get("https://" + path + "?go-get=1")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrRepoRootNotFound
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+	match := goGetMetaTag.FindSubmatch(body)
+	if match == nil {
+		return nil, ErrRepoRootNotFound
+	}
+	vcs := string(match[2])
+	if vcs != "git" {
+		return nil, fmt.Errorf("%w: %s uses %s", ErrVCSNotSupported, path, vcs)
+	}
+	return &RepoRoot{VCS: vcs, URL: string(match[3])}, nil
+}
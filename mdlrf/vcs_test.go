@@ -0,0 +1,68 @@
+package mdlrf
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveRepoRootWellKnownHost(t *testing.T) {
+	root, err := ResolveRepoRoot("github.com/cyborch/mdlr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.VCS != "git" {
+		t.Fatalf("VCS = %q, want git", root.VCS)
+	}
+	if root.URL != "https://github.com/cyborch/mdlr.git" {
+		t.Fatalf("URL = %q", root.URL)
+	}
+}
+
+// withGoImportServer spins up a TLS test server serving body for any path,
+// points goImportHTTPClient at it for the duration of the test, and returns
+// the bare host:port to pass to discoverGoImportMeta (which always talks
+// https).
+func withGoImportServer(t *testing.T, body string) string {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	prev := goImportHTTPClient
+	goImportHTTPClient = srv.Client()
+	t.Cleanup(func() { goImportHTTPClient = prev })
+
+	return strings.TrimPrefix(srv.URL, "https://")
+}
+
+func TestResolveRepoRootGoImportMetaTag(t *testing.T) {
+	host := withGoImportServer(t, `<html><head><meta name="go-import" content="example.test/pkg git https://example.test/pkg.git"></head></html>`)
+
+	root, err := discoverGoImportMeta(host + "/pkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.VCS != "git" || root.URL != "https://example.test/pkg.git" {
+		t.Fatalf("unexpected RepoRoot: %+v", root)
+	}
+}
+
+func TestResolveRepoRootRejectsNonGit(t *testing.T) {
+	host := withGoImportServer(t, `<html><head><meta name="go-import" content="example.test/pkg hg https://example.test/pkg"></head></html>`)
+
+	_, err := discoverGoImportMeta(host + "/pkg")
+	if !errors.Is(err, ErrVCSNotSupported) {
+		t.Fatalf("expected ErrVCSNotSupported, got %v", err)
+	}
+}
+
+func TestResolveRepoRootNotFound(t *testing.T) {
+	if _, err := ResolveRepoRoot(""); err == nil {
+		t.Fatal("expected an error for an empty path")
+	}
+}
@@ -0,0 +1,112 @@
+package mdlrf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Vendor flattens module specificName (or every module flagged `vendor:
+// true` when specificName is empty): it removes the module's VCS metadata
+// directory and, when the module declares Paths, deletes any file that
+// doesn't match one of those glob filters. It must run after a successful
+// Import, since it destroys the information needed to fetch again. The
+// resulting state (source pin and filter set) is recorded in mdlr.lock so
+// Verify can still detect drift in the flattened tree.
+func (ctx *MdlrCtx) Vendor(specificName string) error {
+	if err := ctx.loadFile(); err != nil {
+		return err
+	}
+	if err := ctx.loadLockFile(); err != nil {
+		return err
+	}
+	var names []string
+	if specificName != "" {
+		if _, exist := ctx.MdlrFile.Modules[specificName]; !exist {
+			return ErrModuleNameNotExist
+		}
+		names = []string{specificName}
+	} else {
+		for n, m := range ctx.MdlrFile.Modules {
+			if m.Vendor {
+				names = append(names, n)
+			}
+		}
+	}
+	for _, name := range names {
+		if err := ctx.applyVendor(name); err != nil {
+			return err
+		}
+	}
+	if err := ctx.LockFile.Persist(); err != nil {
+		return err
+	}
+	return ctx.MdlrFile.Persist()
+}
+
+// applyVendor strips VCS metadata and filters paths for an already-imported
+// module, recording the result in ctx.LockFile. It assumes MdlrFile and
+// LockFile are already loaded.
+func (ctx *MdlrCtx) applyVendor(name string) error {
+	m := ctx.MdlrFile.Modules[name]
+	if len(m.Paths) > 0 {
+		if err := filterPaths(m.AbsolutePath, m.Paths); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(m.AbsolutePath, ".git")); err != nil {
+		return err
+	}
+	hash, err := hashTree(m.AbsolutePath)
+	if err != nil {
+		return err
+	}
+	ctx.LockFile.Modules[name] = &LockedModule{
+		Commit:   m.Pin(),
+		TreeHash: hash,
+		Vendored: true,
+		Paths:    m.Paths,
+	}
+	return nil
+}
+
+// filterPaths removes every file under root that does not match at least
+// one of globs. A glob ending in "/**" matches everything under that
+// prefix; otherwise it is matched with filepath.Match against the path
+// relative to root.
+func filterPaths(root string, globs []string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root || info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == filepath.Join(".git") || strings.HasPrefix(rel, ".git"+string(filepath.Separator)) {
+			return nil
+		}
+		if matchesAny(rel, globs) {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+func matchesAny(rel string, globs []string) bool {
+	for _, g := range globs {
+		if strings.HasSuffix(g, "/**") {
+			if strings.HasPrefix(rel, strings.TrimSuffix(g, "**")) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(g, rel); ok {
+			return true
+		}
+	}
+	return false
+}
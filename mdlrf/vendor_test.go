@@ -0,0 +1,77 @@
+package mdlrf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyVendorStripsGitAndFiltersPaths(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".git/HEAD", "ref: refs/heads/main")
+	write("src/main.go", "package main")
+	write("LICENSE", "MIT")
+	write("README.md", "unwanted")
+
+	ctx := &MdlrCtx{
+		MdlrFile: &MdlrFile{Modules: map[string]*Module{
+			"mod": {
+				Name:         "mod",
+				Commit:       "abc123",
+				AbsolutePath: dir,
+				Paths:        []string{"src/**", "LICENSE"},
+			},
+		}},
+		LockFile: NewMdlrLockFile(filepath.Join(dir, "..", "mdlr.lock")),
+	}
+
+	if err := ctx.applyVendor("mod"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Fatalf(".git still present after vendoring: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "main.go")); err != nil {
+		t.Fatalf("expected src/main.go to survive filtering: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "LICENSE")); err != nil {
+		t.Fatalf("expected LICENSE to survive filtering: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); !os.IsNotExist(err) {
+		t.Fatal("expected README.md to be removed by the path filter")
+	}
+
+	locked, ok := ctx.LockFile.Modules["mod"]
+	if !ok {
+		t.Fatal("applyVendor did not record a lockfile entry")
+	}
+	if !locked.Vendored || locked.Commit != "abc123" {
+		t.Fatalf("unexpected lockfile entry: %+v", locked)
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	globs := []string{"src/**", "LICENSE"}
+	cases := map[string]bool{
+		"src/main.go":    true,
+		"src/pkg/a.go":   true,
+		"LICENSE":        true,
+		"README.md":      false,
+		"src_other/a.go": false,
+	}
+	for rel, want := range cases {
+		if got := matchesAny(rel, globs); got != want {
+			t.Errorf("matchesAny(%q, %v) = %v, want %v", rel, globs, got, want)
+		}
+	}
+}